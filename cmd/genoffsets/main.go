@@ -0,0 +1,88 @@
+// Copyright 2022 CFC4N <cfc4n.cs@gmail.com>. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command genoffsets regenerates the fallback offset table in
+// user/config/gooffsets by reading the DWARF debug info of a set of
+// reference Go binaries. Maintainers run this whenever a new Go release
+// ships, rather than hand-editing the table:
+//
+//	go run ./cmd/genoffsets -go-version go1.23 -os linux -arch amd64 /path/to/go1.23-built-binary
+//
+// The target binary must be built with DWARF info (the default, i.e. without
+// -ldflags="-s -w") and for the GOOS/GOARCH passed on the command line. The
+// tool prints a Go source fragment for the `table` map in gooffsets.go; it
+// does not write files itself so the maintainer can review the diff before
+// merging it.
+package main
+
+import (
+	"debug/elf"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/gojue/ecapture/user/config/elfutil"
+)
+
+var structFields = []struct {
+	structName string
+	fieldName  string
+	label      string
+}{
+	{"runtime.g", "goid", "GoidOffset"},
+	{"crypto/tls.Conn", "conn", "TLSConn.Conn"},
+	{"crypto/tls.Conn", "vers", "TLSConn.Vers"},
+	{"crypto/tls.Conn", "cipherSuite", "TLSConn.CipherSuite"},
+	{"net.conn", "fd", "NetFD.Fd"},
+	{"internal/poll.FD", "Sysfd", "NetFD.Sysfd"},
+}
+
+func main() {
+	goos := flag.String("os", "linux", "GOOS the reference binary was built for")
+	arch := flag.String("arch", "amd64", "GOARCH the reference binary was built for")
+	goVersion := flag.String("go-version", "", "Go toolchain version, e.g. go1.22 (required)")
+	flag.Parse()
+
+	if *goVersion == "" || flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: genoffsets -go-version go1.22 [-os linux] [-arch amd64] <reference-binary>")
+		os.Exit(2)
+	}
+
+	f, err := elf.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("open ELF: %v", err)
+	}
+	defer f.Close()
+
+	d, err := f.DWARF()
+	if err != nil {
+		log.Fatalf("reference binary has no DWARF info: %v", err)
+	}
+
+	offsets := make(map[string]uint64, len(structFields))
+	for _, sf := range structFields {
+		offset, err := elfutil.StructFieldOffset(d, sf.structName, sf.fieldName)
+		if err != nil {
+			log.Fatalf("%s.%s: %v", sf.structName, sf.fieldName, err)
+		}
+		offsets[sf.label] = offset
+	}
+
+	fmt.Printf("\t{%q, %q, %q}: {GoidOffset: %d, TLSConn: TLSConnOffsets{Conn: %d, Vers: %d, CipherSuite: %d}, NetFD: NetFDOffsets{Fd: %d, Sysfd: %d}},\n",
+		*goVersion, *goos, *arch,
+		offsets["GoidOffset"],
+		offsets["TLSConn.Conn"], offsets["TLSConn.Vers"], offsets["TLSConn.CipherSuite"],
+		offsets["NetFD.Fd"], offsets["NetFD.Sysfd"])
+}
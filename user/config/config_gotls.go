@@ -16,6 +16,7 @@ package config
 
 import (
 	"debug/elf"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"os"
@@ -23,6 +24,9 @@ import (
 
 	"golang.org/x/arch/arm64/arm64asm"
 	"golang.org/x/arch/x86/x86asm"
+
+	"github.com/gojue/ecapture/user/config/elfutil"
+	"github.com/gojue/ecapture/user/config/gooffsets"
 )
 
 const (
@@ -30,26 +34,72 @@ const (
 	Arm64armInstSize = 4
 
 	GoTlsReadFunc = "crypto/tls.(*Conn).Read"
+
+	// GoTlsWriteFunc is hooked to capture plaintext egress.
+	GoTlsWriteFunc = "crypto/tls.(*Conn).Write"
+	// GoTlsWriteRecordLockedFunc is hooked in addition to Write to capture
+	// TLS 1.3 KeyUpdate records, which bypass (*Conn).Write.
+	GoTlsWriteRecordLockedFunc = "crypto/tls.(*Conn).writeRecordLocked"
+	// GoTlsClientHandshakeFunc and GoTlsServerHandshakeFunc are hooked to
+	// snapshot Conn.ekm and derive the traffic secrets needed for NSS keylog output.
+	GoTlsClientHandshakeFunc = "crypto/tls.(*Conn).clientHandshake"
+	GoTlsServerHandshakeFunc = "crypto/tls.(*Conn).serverHandshake"
+
+	// retOpcode and retImm16Opcode are the x86 RET opcodes used to resync
+	// decodeInstruction after x86asm.Decode fails mid-function.
+	retOpcode      = 0xc3
+	retImm16Opcode = 0xc2
+	retImm16Size   = 3
+
+	// arm64RetEncoding is the little-endian uint32 encoding of "RET" with the
+	// default x30 link register (arm64/arm64asm/decode.go's RET case), used to
+	// resync decodeInstruction's arm64 branch after arm64asm.Decode fails
+	// mid-function.
+	arm64RetEncoding = 0xd65f03c0
+
+	// RuntimeTlsgSymbolAmd64 holds the offset of the current *g from the fs-based TLS base on amd64.
+	RuntimeTlsgSymbolAmd64 = "runtime.tlsg"
+	// RuntimeTlsgSymbolArm64 holds the offset of the current *g from the TPIDR_EL0-based TLS base on arm64.
+	RuntimeTlsgSymbolArm64 = "runtime.tls_g"
+
+	runtimeGStructName = "runtime.g"
+	goidFieldName      = "goid"
+
+	tlsConnStructName  = "crypto/tls.Conn"
+	netConnStructName  = "net.conn"
+	pollFDStructName   = "internal/poll.FD"
+	tlsConnConnField   = "conn"
+	netConnFdField     = "fd"
+	pollFDSysfdField   = "Sysfd"
+	tlsConnVersField   = "vers"
+	tlsConnCipherField = "cipherSuite"
 )
 
 var (
-	ErrorGoBINNotFound  = errors.New("The executable program (compiled by Golang) was not found")
-	ErrorSymbolNotFound = errors.New("symbol not found")
-	ErrorNoRetFound     = errors.New("no RET instructions found")
+	ErrorGoBINNotFound      = errors.New("The executable program (compiled by Golang) was not found")
+	ErrorSymbolNotFound     = errors.New("symbol not found")
+	ErrorNoRetFound         = errors.New("no RET instructions found")
+	ErrorGoidOffsetNotFound = errors.New("goid offset not found, unknown Go version")
 )
 
 // GoTLSConfig represents configuration for Go SSL probe
 type GoTLSConfig struct {
 	eConfig
-	Path         string    `json:"path"`       // golang application path to binary built with Go toolchain.
-	PcapFile     string    `json:"pcapFile"`   // pcapFile  the  raw  packets  to file rather than parsing and printing them out.
-	KeylogFile   string    `json:"keylogFile"` // keylogFile  The file stores SSL/TLS keys, and eCapture captures these keys during encrypted traffic communication and saves them to the file.
-	Model        string    `json:"model"`      // model  such as : text, pcapng/pcap, key/keylog.
-	Ifname       string    `json:"ifName"`     // (TC Classifier) Interface name on which the probe will be attached.
-	PcapFilter   string    `json:"pcapFilter"` // pcap filter
-	goElfArch    string    //
-	goElf        *elf.File //
-	ReadTlsAddrs []int
+	Path           string           `json:"path"`       // golang application path to binary built with Go toolchain.
+	PcapFile       string           `json:"pcapFile"`   // pcapFile  the  raw  packets  to file rather than parsing and printing them out.
+	KeylogFile     string           `json:"keylogFile"` // keylogFile  The file stores SSL/TLS keys, and eCapture captures these keys during encrypted traffic communication and saves them to the file.
+	Model          string           `json:"model"`      // model  such as : text, pcapng/pcap, key/keylog.
+	Ifname         string           `json:"ifName"`     // (TC Classifier) Interface name on which the probe will be attached.
+	PcapFilter     string           `json:"pcapFilter"` // pcap filter
+	goElfArch      string           //
+	goElf          *elf.File        //
+	ReadTlsAddrs   []int            // RET offsets within crypto/tls.(*Conn).Read; see findRetOffsets for PIE semantics
+	WriteTlsAddrs  []int            // RET offsets within crypto/tls.(*Conn).Write; see findRetOffsets for PIE semantics
+	HandshakeAddrs map[string][]int // RET offsets within writeRecordLocked/clientHandshake/serverHandshake, keyed by symbol name
+	TlsgOffset     uint64           // offset of the current *g from the TLS base (fs on amd64, TPIDR_EL0 on arm64)
+	GoidOffset     uint64           // offset of the goid field within runtime.g
+	TLSConnOffsets gooffsets.TLSConnOffsets
+	NetFDOffsets   gooffsets.NetFDOffsets
 }
 
 // NewGoTLSConfig creates a new config for Go SSL
@@ -103,13 +153,317 @@ func (gc *GoTLSConfig) Check() error {
 	gc.goElfArch = goElfArch
 	gc.goElf = goElf
 	gc.ReadTlsAddrs, err = gc.findRetOffsets(GoTlsReadFunc)
+	if err != nil {
+		return err
+	}
+
+	gc.WriteTlsAddrs, err = gc.findRetOffsets(GoTlsWriteFunc)
+	if err != nil {
+		return err
+	}
+
+	gc.HandshakeAddrs = make(map[string][]int, 3)
+	for _, symbolName := range []string{GoTlsWriteRecordLockedFunc, GoTlsClientHandshakeFunc, GoTlsServerHandshakeFunc} {
+		addrs, findErr := gc.findRetOffsets(symbolName)
+		if findErr != nil {
+			// A purely client-only (or server-only) binary can have
+			// serverHandshake (or clientHandshake) dead-code-eliminated by
+			// the linker, since it's only ever reached via the handshakeFn
+			// that tls.Client/tls.Server assign at runtime. Skip whatever
+			// isn't present rather than failing probe setup over it.
+			continue
+		}
+		gc.HandshakeAddrs[symbolName] = addrs
+	}
+
+	gc.TlsgOffset, err = gc.findTlsgOffset()
+	if err != nil {
+		return err
+	}
+
+	gc.GoidOffset, err = gc.findGoidOffset()
+	if err != nil {
+		return err
+	}
+
+	gc.TLSConnOffsets, err = gc.findTLSConnOffsets()
+	if err != nil {
+		return err
+	}
+
+	gc.NetFDOffsets, err = gc.findNetFDOffsets()
 	return err
 }
 
+// findTLSConnOffsets resolves the crypto/tls.Conn field offsets the probe
+// needs to read the socket, TLS version and cipher suite of a connection.
+func (gc *GoTLSConfig) findTLSConnOffsets() (gooffsets.TLSConnOffsets, error) {
+	conn, connErr := gc.findStructFieldOffsetDWARF(tlsConnStructName, tlsConnConnField)
+	vers, versErr := gc.findStructFieldOffsetDWARF(tlsConnStructName, tlsConnVersField)
+	cipher, cipherErr := gc.findStructFieldOffsetDWARF(tlsConnStructName, tlsConnCipherField)
+	if connErr == nil && versErr == nil && cipherErr == nil {
+		return gooffsets.TLSConnOffsets{Conn: conn, Vers: vers, CipherSuite: cipher}, nil
+	}
+
+	// The Go version is only needed as a fallback key when DWARF lookup
+	// fails, so it's resolved lazily here rather than unconditionally in
+	// Check() -- a binary with full DWARF info never needs it.
+	goVersion, verErr := gc.findGoVersion()
+	if verErr != nil {
+		return gooffsets.TLSConnOffsets{}, fmt.Errorf("crypto/tls.Conn offsets: no DWARF info and %w", verErr)
+	}
+	offsets, err := gooffsets.Lookup(goVersion, runtime.GOOS, gc.goElfArch)
+	if err != nil {
+		return gooffsets.TLSConnOffsets{}, fmt.Errorf("crypto/tls.Conn offsets: no DWARF info and %w", err)
+	}
+	return offsets.TLSConn, nil
+}
+
+// findNetFDOffsets resolves the offsets needed to walk from a net.Conn down
+// to its underlying file descriptor.
+func (gc *GoTLSConfig) findNetFDOffsets() (gooffsets.NetFDOffsets, error) {
+	fd, fdErr := gc.findStructFieldOffsetDWARF(netConnStructName, netConnFdField)
+	sysfd, sysfdErr := gc.findStructFieldOffsetDWARF(pollFDStructName, pollFDSysfdField)
+	if fdErr == nil && sysfdErr == nil {
+		return gooffsets.NetFDOffsets{Fd: fd, Sysfd: sysfd}, nil
+	}
+
+	goVersion, verErr := gc.findGoVersion()
+	if verErr != nil {
+		return gooffsets.NetFDOffsets{}, fmt.Errorf("net.conn/poll.FD offsets: no DWARF info and %w", verErr)
+	}
+	offsets, err := gooffsets.Lookup(goVersion, runtime.GOOS, gc.goElfArch)
+	if err != nil {
+		return gooffsets.NetFDOffsets{}, fmt.Errorf("net.conn/poll.FD offsets: no DWARF info and %w", err)
+	}
+	return offsets.NetFD, nil
+}
+
+// findTlsgOffset locates the runtime.tlsg (amd64) / runtime.tls_g (arm64) symbol,
+// whose Value is the offset of the current *g from the architecture's TLS base.
+func (gc *GoTLSConfig) findTlsgOffset() (uint64, error) {
+	symbolName := RuntimeTlsgSymbolAmd64
+	if gc.goElfArch == "arm64" {
+		symbolName = RuntimeTlsgSymbolArm64
+	}
+
+	symbols, err := gc.goElf.Symbols()
+	if err != nil {
+		return 0, err
+	}
+	for _, s := range symbols {
+		if s.Name == symbolName {
+			return s.Value, nil
+		}
+	}
+	return 0, ErrorSymbolNotFound
+}
+
+// findGoidOffset resolves the offset of the goid field within runtime.g, preferring
+// DWARF debug info and falling back to the gooffsets database for stripped binaries.
+func (gc *GoTLSConfig) findGoidOffset() (uint64, error) {
+	offset, err := gc.findStructFieldOffsetDWARF(runtimeGStructName, goidFieldName)
+	if err == nil {
+		return offset, nil
+	}
+
+	goVersion, verErr := gc.findGoVersion()
+	if verErr != nil {
+		return 0, ErrorGoidOffsetNotFound
+	}
+	offsets, lookupErr := gooffsets.Lookup(goVersion, runtime.GOOS, gc.goElfArch)
+	if lookupErr != nil {
+		return 0, ErrorGoidOffsetNotFound
+	}
+	return offsets.GoidOffset, nil
+}
+
+// findStructFieldOffsetDWARF walks the DWARF type info of the target binary
+// and returns the byte offset of fieldName within structName.
+func (gc *GoTLSConfig) findStructFieldOffsetDWARF(structName, fieldName string) (uint64, error) {
+	d, err := gc.goElf.DWARF()
+	if err != nil {
+		return 0, err
+	}
+	return elfutil.StructFieldOffset(d, structName, fieldName)
+}
+
+// buildInfoMagic is the fixed prefix of the .go.buildinfo section, as
+// written by the linker (see runtime/symtab.go's buildVersion / modinfo).
+const buildInfoMagic = "\xff Go buildinf:"
+
+// buildInfoHeaderSize is the size of the fixed-layout header preceding the
+// version/modinfo payload: magic (14 bytes), ptrSize (1 byte), flags (1
+// byte), then two ptrSize-wide words.
+const buildInfoHeaderSize = 32
+
+// findGoVersion reads the Go toolchain version the binary was built with from
+// the .go.buildinfo section, truncated to the "goMAJOR.MINOR" form (e.g.
+// "go1.20.3" becomes "go1.20") used to key the gooffsets database.
+func (gc *GoTLSConfig) findGoVersion() (string, error) {
+	section := gc.goElf.Section(".go.buildinfo")
+	if section == nil {
+		return "", fmt.Errorf("section .go.buildinfo not found")
+	}
+	data, err := section.Data()
+	if err != nil {
+		return "", err
+	}
+
+	vers, err := parseGoBuildInfo(gc.goElf, data)
+	if err != nil {
+		return "", err
+	}
+	return truncateGoVersion(vers), nil
+}
+
+// parseGoBuildInfo extracts the raw Go version string (e.g. "go1.20.3") from
+// a .go.buildinfo section's data. The layout has two encodings, matched on
+// the flags byte exactly as the stdlib's debug/buildinfo does:
+//
+//   - flags&0x2 != 0 (Go 1.18+): the version/modinfo strings are inlined as
+//     length-prefixed (varint) data right after the 32-byte header.
+//   - flags&0x2 == 0 (older toolchains): the header instead holds a pointer
+//     to a Go string header (data pointer + length) elsewhere in the binary,
+//     which has to be read back out of the ELF by virtual address.
+func parseGoBuildInfo(goElf *elf.File, data []byte) (string, error) {
+	if len(data) < buildInfoHeaderSize || string(data[:len(buildInfoMagic)]) != buildInfoMagic {
+		return "", fmt.Errorf("invalid .go.buildinfo magic")
+	}
+
+	ptrSize := int(data[14])
+	flags := data[15]
+	if ptrSize != 4 && ptrSize != 8 {
+		return "", fmt.Errorf("unsupported .go.buildinfo pointer size %d", ptrSize)
+	}
+	bigEndian := flags&0x1 != 0
+
+	if flags&0x2 != 0 {
+		vers, _, err := decodeInlineString(data[buildInfoHeaderSize:])
+		if err != nil {
+			return "", fmt.Errorf("decode inline .go.buildinfo version: %w", err)
+		}
+		return vers, nil
+	}
+
+	if goElf == nil {
+		return "", fmt.Errorf("pointer-encoded .go.buildinfo requires the source ELF")
+	}
+	if len(data) < 16+ptrSize {
+		return "", fmt.Errorf("short .go.buildinfo header")
+	}
+	versAddr, err := readUintPtr(data[16:16+ptrSize], ptrSize, bigEndian)
+	if err != nil {
+		return "", err
+	}
+	vers, err := readGoStringAt(goElf, versAddr, ptrSize, bigEndian)
+	if err != nil {
+		return "", fmt.Errorf("read pointer-encoded .go.buildinfo version: %w", err)
+	}
+	return vers, nil
+}
+
+// truncateGoVersion trims a raw "go1.20.3"-style version string down to the
+// "go1.20" form used to key the gooffsets database.
+func truncateGoVersion(vers string) string {
+	dots := 0
+	for i := 0; i < len(vers); i++ {
+		if vers[i] == '.' {
+			dots++
+			if dots == 2 {
+				return vers[:i]
+			}
+		}
+	}
+	return vers
+}
+
+// decodeInlineString reads a Uvarint length followed by that many bytes of
+// string data, matching the encoding the Go linker uses for the inlined
+// .go.buildinfo payload (binary.Uvarint is the same LEB128 encoding).
+func decodeInlineString(data []byte) (string, []byte, error) {
+	length, n := binary.Uvarint(data)
+	if n <= 0 {
+		return "", nil, fmt.Errorf("invalid length prefix")
+	}
+	if uint64(n)+length > uint64(len(data)) {
+		return "", nil, fmt.Errorf("string length %d exceeds available data", length)
+	}
+	return string(data[n : n+int(length)]), data[n+int(length):], nil
+}
+
+// readUintPtr decodes a ptrSize-wide unsigned integer from b in the given byte order.
+func readUintPtr(b []byte, ptrSize int, bigEndian bool) (uint64, error) {
+	switch ptrSize {
+	case 4:
+		if bigEndian {
+			return uint64(binary.BigEndian.Uint32(b)), nil
+		}
+		return uint64(binary.LittleEndian.Uint32(b)), nil
+	case 8:
+		if bigEndian {
+			return binary.BigEndian.Uint64(b), nil
+		}
+		return binary.LittleEndian.Uint64(b), nil
+	default:
+		return 0, fmt.Errorf("unsupported pointer size %d", ptrSize)
+	}
+}
+
+// readBytesAtAddr returns length bytes read from the ELF section that
+// contains the given virtual address.
+func readBytesAtAddr(goElf *elf.File, addr uint64, length int) ([]byte, error) {
+	for _, s := range goElf.Sections {
+		if addr < s.Addr || addr >= s.Addr+s.Size {
+			continue
+		}
+		data, err := s.Data()
+		if err != nil {
+			return nil, err
+		}
+		off := addr - s.Addr
+		if off+uint64(length) > uint64(len(data)) {
+			return nil, fmt.Errorf("read past end of section %s", s.Name)
+		}
+		return data[off : off+uint64(length)], nil
+	}
+	return nil, fmt.Errorf("no section contains address 0x%x", addr)
+}
+
+// readGoStringAt reads a Go string header (data pointer + length, each
+// ptrSize wide) at the given virtual address, then reads back the string
+// bytes it points to.
+func readGoStringAt(goElf *elf.File, addr uint64, ptrSize int, bigEndian bool) (string, error) {
+	hdr, err := readBytesAtAddr(goElf, addr, ptrSize*2)
+	if err != nil {
+		return "", err
+	}
+	strAddr, err := readUintPtr(hdr[:ptrSize], ptrSize, bigEndian)
+	if err != nil {
+		return "", err
+	}
+	strLen, err := readUintPtr(hdr[ptrSize:], ptrSize, bigEndian)
+	if err != nil {
+		return "", err
+	}
+	strBytes, err := readBytesAtAddr(goElf, strAddr, int(strLen))
+	if err != nil {
+		return "", err
+	}
+	return string(strBytes), nil
+}
+
 // FindRetOffsets searches for the addresses of all RET instructions within
 // the instruction set associated with the specified symbol in an ELF program.
 // It is used for mounting uretprobe programs for Golang programs,
 // which are actually mounted via uprobe on these addresses.
+//
+// For ET_EXEC binaries the returned offsets are relative to the start of the
+// symbol, matching the fixed load base assumed by non-PIE Go binaries. For
+// ET_DYN (-buildmode=pie) binaries, which are increasingly the distro
+// default, they are absolute ELF file offsets instead, since uprobe
+// attachment on a PIE binary needs a file offset rather than a virtual
+// address computed against a load base that does not exist until runtime.
 func (gc *GoTLSConfig) findRetOffsets(symbolName string) ([]int, error) {
 	var err error
 	var allSymbs []elf.Symbol
@@ -156,13 +510,68 @@ func (gc *GoTLSConfig) findRetOffsets(symbolName string) ([]int, error) {
 	var offsets []int
 	var instHex []byte
 	instHex = elfText[start:end]
-	offsets, _ = gc.decodeInstruction(instHex)
+	offsets, err = gc.decodeInstruction(instHex)
+	if err != nil {
+		return nil, err
+	}
 	if len(offsets) == 0 {
 		return offsets, ErrorNoRetFound
 	}
+
+	if gc.goElf.FileHeader.Type == elf.ET_DYN {
+		return gc.toFileOffsets(symbol.Value, offsets)
+	}
 	return offsets, nil
 }
 
+// toFileOffsets translates RET offsets that are relative to a symbol's start
+// into absolute ELF file offsets, for position-independent (ET_DYN) Go
+// binaries where the eventual load base is not known ahead of time.
+func (gc *GoTLSConfig) toFileOffsets(symbolValue uint64, relOffsets []int) ([]int, error) {
+	prog := findLoadSegment(gc.goElf.Progs, symbolValue)
+	if prog == nil {
+		return nil, fmt.Errorf("no PT_LOAD segment contains address 0x%x", symbolValue)
+	}
+	return segmentFileOffsets(prog, symbolValue, relOffsets), nil
+}
+
+// findLoadSegment returns the PT_LOAD segment whose virtual address range contains addr.
+func findLoadSegment(progs []*elf.Prog, addr uint64) *elf.Prog {
+	for _, prog := range progs {
+		if prog.Type != elf.PT_LOAD {
+			continue
+		}
+		if addr >= prog.Vaddr && addr < prog.Vaddr+prog.Memsz {
+			return prog
+		}
+	}
+	return nil
+}
+
+// segmentFileOffsets translates symbol-relative offsets into absolute ELF
+// file offsets, given the PT_LOAD segment that contains the symbol.
+func segmentFileOffsets(prog *elf.Prog, symbolValue uint64, relOffsets []int) []int {
+	fileOffsets := make([]int, len(relOffsets))
+	for i, rel := range relOffsets {
+		fileOffsets[i] = int(symbolValue-prog.Vaddr+prog.Off) + rel
+	}
+	return fileOffsets
+}
+
+// HandshakeSymbols returns the symbol names whose RET offsets are available via HandshakeAddrs.
+func (gc *GoTLSConfig) HandshakeSymbols() []string {
+	symbols := make([]string, 0, len(gc.HandshakeAddrs))
+	for symbolName := range gc.HandshakeAddrs {
+		symbols = append(symbols, symbolName)
+	}
+	return symbols
+}
+
+// HandshakeAddrsFor returns the RET offsets recorded for the given handshake symbol.
+func (gc *GoTLSConfig) HandshakeAddrsFor(symbolName string) []int {
+	return gc.HandshakeAddrs[symbolName]
+}
+
 // decodeInstruction Decode into assembly instructions and identify the RET instruction to return the offset.
 func (gc *GoTLSConfig) decodeInstruction(instHex []byte) ([]int, error) {
 	var offsets []int
@@ -170,7 +579,17 @@ func (gc *GoTLSConfig) decodeInstruction(instHex []byte) ([]int, error) {
 		if gc.goElfArch == "amd64" {
 			inst, err := x86asm.Decode(instHex[i:], 64)
 			if err != nil {
-				return nil, err
+				// Larger, inlining-heavy functions (e.g. Write on stripped release
+				// binaries) can embed jump tables or other non-instruction data
+				// that x86asm cannot decode. Instead of aborting the whole
+				// function, resync on the next RET opcode byte and keep scanning.
+				skip, instLen, ok := resyncOnRet(instHex[i:])
+				if !ok {
+					return offsets, nil
+				}
+				offsets = append(offsets, i+skip)
+				i += skip + instLen
+				continue
 			}
 			if inst.Op == x86asm.RET {
 				offsets = append(offsets, i)
@@ -179,7 +598,16 @@ func (gc *GoTLSConfig) decodeInstruction(instHex []byte) ([]int, error) {
 		} else {
 			inst, err := arm64asm.Decode(instHex[i:])
 			if err != nil {
-				return nil, err
+				// As on amd64, larger functions on stripped release binaries can
+				// embed a jump table or literal pool word that arm64asm cannot
+				// decode as an instruction. arm64 is fixed-width, so resyncing
+				// only needs to check whether the undecodable word itself is a
+				// RET encoding before skipping past it and continuing to scan.
+				if i+Arm64armInstSize <= len(instHex) && binary.LittleEndian.Uint32(instHex[i:i+Arm64armInstSize]) == arm64RetEncoding {
+					offsets = append(offsets, i)
+				}
+				i += Arm64armInstSize
+				continue
 			}
 			if inst.Op == arm64asm.RET {
 				offsets = append(offsets, i)
@@ -190,6 +618,24 @@ func (gc *GoTLSConfig) decodeInstruction(instHex []byte) ([]int, error) {
 	return offsets, nil
 }
 
+// resyncOnRet scans forward from the start of b for the next byte sequence
+// that looks like a RET (0xc3) or RET imm16 (0xc2 iw) opcode, returning its
+// offset and instruction length. It is used to recover from x86asm.Decode
+// errors without giving up on the rest of the function.
+func resyncOnRet(b []byte) (offset int, instLen int, ok bool) {
+	for i, c := range b {
+		switch c {
+		case retOpcode:
+			return i, 1, true
+		case retImm16Opcode:
+			if i+retImm16Size <= len(b) {
+				return i, retImm16Size, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
 func (gc *GoTLSConfig) checkModel() (string, error) {
 	var m string
 	var e error
@@ -0,0 +1,411 @@
+// Copyright 2022 CFC4N <cfc4n.cs@gmail.com>. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package config
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResyncOnRet(t *testing.T) {
+	cases := []struct {
+		name       string
+		b          []byte
+		wantOffset int
+		wantLen    int
+		wantOK     bool
+	}{
+		{"ret at start", []byte{0xc3, 0x90}, 0, 1, true},
+		{"ret after junk", []byte{0xff, 0xff, 0xc3}, 2, 1, true},
+		{"ret imm16", []byte{0x90, 0xc2, 0x08, 0x00}, 1, 3, true},
+		{"truncated ret imm16 ignored", []byte{0x90, 0xc2, 0x08}, 0, 0, false},
+		{"no ret", []byte{0x90, 0x90, 0x90}, 0, 0, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			offset, instLen, ok := resyncOnRet(tc.b)
+			if ok != tc.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tc.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if offset != tc.wantOffset || instLen != tc.wantLen {
+				t.Fatalf("got (offset=%d, len=%d), want (offset=%d, len=%d)", offset, instLen, tc.wantOffset, tc.wantLen)
+			}
+		})
+	}
+}
+
+func TestDecodeInstructionAmd64(t *testing.T) {
+	gc := &GoTLSConfig{goElfArch: "amd64"}
+
+	// NOP (0x90), RET (0xc3): a single RET at offset 1.
+	offsets, err := gc.decodeInstruction([]byte{0x90, 0xc3})
+	if err != nil {
+		t.Fatalf("decodeInstruction: %v", err)
+	}
+	if !reflect.DeepEqual(offsets, []int{1}) {
+		t.Fatalf("offsets = %v, want [1]", offsets)
+	}
+}
+
+func TestDecodeInstructionArm64(t *testing.T) {
+	gc := &GoTLSConfig{goElfArch: "arm64"}
+
+	// A single RET (0xd65f03c0, little-endian) at offset 0.
+	offsets, err := gc.decodeInstruction([]byte{0xc0, 0x03, 0x5f, 0xd6})
+	if err != nil {
+		t.Fatalf("decodeInstruction: %v", err)
+	}
+	if !reflect.DeepEqual(offsets, []int{0}) {
+		t.Fatalf("offsets = %v, want [0]", offsets)
+	}
+}
+
+func TestDecodeInstructionArm64ResyncsPastUndecodableWord(t *testing.T) {
+	gc := &GoTLSConfig{goElfArch: "arm64"}
+
+	// An undecodable word (reserved/UNDEFINED encoding) followed by a RET:
+	// decodeInstruction should skip the undecodable word instead of
+	// aborting, and still find the RET that follows it.
+	instHex := []byte{0xff, 0xff, 0xff, 0xff, 0xc0, 0x03, 0x5f, 0xd6}
+	offsets, err := gc.decodeInstruction(instHex)
+	if err != nil {
+		t.Fatalf("decodeInstruction: %v", err)
+	}
+	if !reflect.DeepEqual(offsets, []int{4}) {
+		t.Fatalf("offsets = %v, want [4]", offsets)
+	}
+}
+
+func TestFindLoadSegmentAndSegmentFileOffsets(t *testing.T) {
+	progs := []*elf.Prog{
+		{ProgHeader: elf.ProgHeader{Type: elf.PT_NOTE, Vaddr: 0x1000, Memsz: 0x100}},
+		{ProgHeader: elf.ProgHeader{Type: elf.PT_LOAD, Vaddr: 0x400000, Off: 0, Memsz: 0x1000}},
+		{ProgHeader: elf.ProgHeader{Type: elf.PT_LOAD, Vaddr: 0x500000, Off: 0x100000, Memsz: 0x2000}},
+	}
+
+	prog := findLoadSegment(progs, 0x500123)
+	if prog == nil {
+		t.Fatal("expected a matching PT_LOAD segment")
+	}
+	if prog.Vaddr != 0x500000 {
+		t.Fatalf("matched wrong segment: Vaddr=0x%x", prog.Vaddr)
+	}
+
+	if findLoadSegment(progs, 0x900000) != nil {
+		t.Fatal("expected no segment to contain an out-of-range address")
+	}
+
+	fileOffsets := segmentFileOffsets(prog, 0x500123, []int{0, 4})
+	want := []int{0x100123, 0x100127}
+	if !reflect.DeepEqual(fileOffsets, want) {
+		t.Fatalf("fileOffsets = %#x, want %#x", fileOffsets, want)
+	}
+}
+
+func TestTruncateGoVersion(t *testing.T) {
+	cases := map[string]string{
+		"go1.20.3":  "go1.20",
+		"go1.20":    "go1.20",
+		"go1.22rc1": "go1.22rc1",
+	}
+	for in, want := range cases {
+		if got := truncateGoVersion(in); got != want {
+			t.Errorf("truncateGoVersion(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDecodeInlineString(t *testing.T) {
+	var data []byte
+	data = binary.AppendUvarint(data, uint64(len("go1.20.3")))
+	data = append(data, "go1.20.3"...)
+	data = append(data, "trailing"...)
+
+	s, rest, err := decodeInlineString(data)
+	if err != nil {
+		t.Fatalf("decodeInlineString: %v", err)
+	}
+	if s != "go1.20.3" {
+		t.Fatalf("s = %q, want go1.20.3", s)
+	}
+	if string(rest) != "trailing" {
+		t.Fatalf("rest = %q, want trailing", rest)
+	}
+}
+
+func TestDecodeInlineStringTruncated(t *testing.T) {
+	var data []byte
+	data = binary.AppendUvarint(data, 100)
+	data = append(data, "short"...)
+
+	if _, _, err := decodeInlineString(data); err == nil {
+		t.Fatal("expected error for truncated inline string")
+	}
+}
+
+func TestReadUintPtr(t *testing.T) {
+	b4 := []byte{0x01, 0x02, 0x03, 0x04}
+	if v, err := readUintPtr(b4, 4, false); err != nil || v != 0x04030201 {
+		t.Fatalf("le32: got (%d, %v), want 0x04030201", v, err)
+	}
+	if v, err := readUintPtr(b4, 4, true); err != nil || v != 0x01020304 {
+		t.Fatalf("be32: got (%d, %v), want 0x01020304", v, err)
+	}
+
+	b8 := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+	if v, err := readUintPtr(b8, 8, false); err != nil || v != 0x0807060504030201 {
+		t.Fatalf("le64: got (%d, %v), want 0x0807060504030201", v, err)
+	}
+
+	if _, err := readUintPtr(b4, 2, false); err == nil {
+		t.Fatal("expected error for unsupported pointer size")
+	}
+}
+
+// TestParseGoBuildInfoInline exercises the Go 1.18+ encoding (flags&0x2 != 0),
+// where the version string is inlined after the 32-byte header and doesn't
+// require dereferencing a pointer back into the ELF (so goElf can be nil).
+func TestParseGoBuildInfoInline(t *testing.T) {
+	header := make([]byte, buildInfoHeaderSize)
+	copy(header, buildInfoMagic)
+	header[14] = 8    // ptrSize
+	header[15] = 0x02 // inline encoding, little-endian
+
+	var payload []byte
+	payload = binary.AppendUvarint(payload, uint64(len("go1.21.4")))
+	payload = append(payload, "go1.21.4"...)
+
+	data := append(header, payload...)
+
+	vers, err := parseGoBuildInfo(nil, data)
+	if err != nil {
+		t.Fatalf("parseGoBuildInfo: %v", err)
+	}
+	if vers != "go1.21.4" {
+		t.Fatalf("vers = %q, want go1.21.4", vers)
+	}
+}
+
+func TestParseGoBuildInfoBadMagic(t *testing.T) {
+	data := make([]byte, buildInfoHeaderSize)
+	if _, err := parseGoBuildInfo(nil, data); err == nil {
+		t.Fatal("expected error for invalid magic")
+	}
+}
+
+// TestParseGoBuildInfoPointerEncodingRequiresELF documents that the
+// pre-1.18 pointer encoding needs to dereference an address elsewhere in the
+// binary, which this package cannot fabricate without a real reference ELF
+// (see cmd/genoffsets for how such binaries are captured for the gooffsets
+// table). It only asserts the nil-ELF guard rail fires cleanly instead of
+// panicking.
+func TestParseGoBuildInfoPointerEncodingRequiresELF(t *testing.T) {
+	header := make([]byte, buildInfoHeaderSize)
+	copy(header, buildInfoMagic)
+	header[14] = 8 // ptrSize
+	header[15] = 0 // pointer encoding, little-endian
+
+	if _, err := parseGoBuildInfo(nil, header); err == nil {
+		t.Fatal("expected error when goElf is nil for pointer-encoded buildinfo")
+	}
+}
+
+// referenceSource is a minimal Go program with enough real usage of
+// crypto/tls.Conn and net.conn to keep their DWARF type info and the
+// crypto/tls.(*Conn).Read/Write methods in the linked binary, for the real
+// (non-synthetic) ELF fixtures built by buildReferenceBinary below.
+const referenceSource = `package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+)
+
+func main() {
+	c := &tls.Conn{}
+	read := c.Read
+	write := c.Write
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	defer ln.Close()
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(c, conn, read, write)
+}
+`
+
+// goTool locates a usable "go" binary: first on PATH, then at the
+// conventional /usr/local/go install location used by sandboxes that don't
+// add it to PATH by default.
+func goTool(t *testing.T) string {
+	t.Helper()
+	if path, err := exec.LookPath("go"); err == nil {
+		return path
+	}
+	const fallback = "/usr/local/go/bin/go"
+	if _, err := os.Stat(fallback); err == nil {
+		return fallback
+	}
+	t.Skip("no Go toolchain available to build reference binaries")
+	return ""
+}
+
+// buildReferenceBinary compiles referenceSource for the given GOOS/GOARCH
+// (and, if pie is true, with -buildmode=pie) into dir, returning the binary
+// path. It builds without stripping so the result keeps DWARF and symbols,
+// matching the real-world binaries findRetOffsets/findStructFieldOffsetDWARF
+// are meant to introspect.
+func buildReferenceBinary(t *testing.T, goos, goarch string, pie bool) string {
+	t.Helper()
+	goBin := goTool(t)
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(src, []byte(referenceSource), 0o644); err != nil {
+		t.Fatalf("write reference source: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module refbin\n\ngo 1.21\n"), 0o644); err != nil {
+		t.Fatalf("write go.mod: %v", err)
+	}
+
+	out := filepath.Join(dir, "ref")
+	args := []string{"build", "-o", out}
+	if pie {
+		args = append(args, "-buildmode=pie")
+	}
+	args = append(args, src)
+
+	cmd := exec.Command(goBin, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOOS="+goos, "GOARCH="+goarch, "GOPROXY=off", "GO111MODULE=on")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("building %s/%s reference binary: %v\n%s", goos, goarch, err, out)
+	}
+	return out
+}
+
+// TestFindGoVersionRealBinary exercises findGoVersion against the actual
+// .go.buildinfo section of a real compiled binary, rather than a hand-built
+// header: it's the section layout itself (and the Go 1.18+ inline/pointer
+// flags-byte branch within it) that's under test, and only a binary the
+// toolchain actually produced can tell us that's read correctly.
+func TestFindGoVersionRealBinary(t *testing.T) {
+	bin := buildReferenceBinary(t, "linux", "amd64", false)
+
+	goElf, err := elf.Open(bin)
+	if err != nil {
+		t.Fatalf("elf.Open: %v", err)
+	}
+	defer goElf.Close()
+
+	gc := &GoTLSConfig{goElf: goElf}
+	vers, err := gc.findGoVersion()
+	if err != nil {
+		t.Fatalf("findGoVersion: %v", err)
+	}
+	if !strings.HasPrefix(vers, "go1.") {
+		t.Fatalf("vers = %q, want a goMAJOR.MINOR string", vers)
+	}
+}
+
+// TestFindStructFieldOffsetDWARFRealBinary exercises
+// findStructFieldOffsetDWARF/elfutil.StructFieldOffset end-to-end against the
+// DWARF info of a real compiled binary, for both a non-PIE (ET_EXEC) and a
+// PIE (ET_DYN) build, since PIE binaries carry DWARF the same way but are
+// the ones toFileOffsets exists for.
+func TestFindStructFieldOffsetDWARFRealBinary(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		pie  bool
+	}{
+		{"non-PIE", false},
+		{"PIE", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bin := buildReferenceBinary(t, "linux", "amd64", tc.pie)
+
+			goElf, err := elf.Open(bin)
+			if err != nil {
+				t.Fatalf("elf.Open: %v", err)
+			}
+			defer goElf.Close()
+
+			gc := &GoTLSConfig{goElf: goElf}
+			vers, err := gc.findStructFieldOffsetDWARF(tlsConnStructName, tlsConnVersField)
+			if err != nil {
+				t.Fatalf("findStructFieldOffsetDWARF(vers): %v", err)
+			}
+			cipher, err := gc.findStructFieldOffsetDWARF(tlsConnStructName, tlsConnCipherField)
+			if err != nil {
+				t.Fatalf("findStructFieldOffsetDWARF(cipherSuite): %v", err)
+			}
+			// vers and cipherSuite must be distinct, non-zero offsets; a
+			// table row that put two fields at the same offset would fail
+			// this check against a real binary.
+			if vers == 0 || cipher == 0 || vers == cipher {
+				t.Fatalf("vers=%d cipher=%d, want distinct non-zero offsets", vers, cipher)
+			}
+		})
+	}
+}
+
+// TestFindRetOffsetsRealBinary exercises findRetOffsets/toFileOffsets
+// end-to-end against crypto/tls.(*Conn).Read in a real compiled binary, for
+// both a non-PIE (symbol-relative offsets) and a PIE (absolute file offsets
+// via toFileOffsets) build.
+func TestFindRetOffsetsRealBinary(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		pie  bool
+	}{
+		{"non-PIE", false},
+		{"PIE", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bin := buildReferenceBinary(t, "linux", "amd64", tc.pie)
+
+			goElf, err := elf.Open(bin)
+			if err != nil {
+				t.Fatalf("elf.Open: %v", err)
+			}
+			defer goElf.Close()
+
+			gc := &GoTLSConfig{goElf: goElf, goElfArch: "amd64"}
+			offsets, err := gc.findRetOffsets(GoTlsReadFunc)
+			if err != nil {
+				t.Fatalf("findRetOffsets: %v", err)
+			}
+			if len(offsets) == 0 {
+				t.Fatal("expected at least one RET offset for crypto/tls.(*Conn).Read")
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2022 CFC4N <cfc4n.cs@gmail.com>. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package elfutil holds small DWARF/ELF inspection helpers shared between
+// the Go TLS probe (user/config) and cmd/genoffsets, which regenerates the
+// gooffsets fallback table using the same struct-layout logic.
+package elfutil
+
+import "debug/dwarf"
+
+// StructFieldOffset walks the DWARF type info in d and returns the byte
+// offset of fieldName within structName.
+func StructFieldOffset(d *dwarf.Data, structName, fieldName string) (uint64, error) {
+	reader := d.Reader()
+	for {
+		entry, err := reader.Next()
+		if err != nil {
+			return 0, err
+		}
+		if entry == nil {
+			break
+		}
+		if entry.Tag != dwarf.TagStructType {
+			continue
+		}
+		name, _ := entry.Val(dwarf.AttrName).(string)
+		if name != structName {
+			continue
+		}
+
+		for {
+			child, err := reader.Next()
+			if err != nil {
+				return 0, err
+			}
+			if child == nil || child.Tag == 0 {
+				break
+			}
+			if child.Tag != dwarf.TagMember {
+				continue
+			}
+			memberName, _ := child.Val(dwarf.AttrName).(string)
+			if memberName == fieldName {
+				loc, _ := child.Val(dwarf.AttrDataMemberLoc).(int64)
+				return uint64(loc), nil
+			}
+		}
+		return 0, errStructFieldNotFound(structName, fieldName)
+	}
+	return 0, errStructFieldNotFound(structName, fieldName)
+}
+
+func errStructFieldNotFound(structName, fieldName string) error {
+	return &fieldNotFoundError{structName: structName, fieldName: fieldName}
+}
+
+// fieldNotFoundError is returned when structName has no field fieldName, or
+// structName itself is not present in the DWARF info.
+type fieldNotFoundError struct {
+	structName string
+	fieldName  string
+}
+
+func (e *fieldNotFoundError) Error() string {
+	return "elfutil: " + e.structName + "." + e.fieldName + " not found"
+}
@@ -0,0 +1,137 @@
+// Copyright 2022 CFC4N <cfc4n.cs@gmail.com>. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elfutil
+
+import (
+	"bytes"
+	"debug/dwarf"
+	"encoding/binary"
+	"testing"
+)
+
+const (
+	tagCompileUnit   = 0x11
+	tagStructureType = 0x13
+	tagMember        = 0x0d
+	atName           = 0x03
+	atDataMemberLoc  = 0x38
+	formString       = 0x08
+	formData1        = 0x0b
+)
+
+func uleb128(v uint64) []byte {
+	var buf []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			b |= 0x80
+		}
+		buf = append(buf, b)
+		if v == 0 {
+			break
+		}
+	}
+	return buf
+}
+
+// buildTestDWARF returns a minimal DWARF v2 blob describing:
+//
+//	struct runtime.g { goid <data_member_location=152> }
+func buildTestDWARF(t *testing.T) *dwarf.Data {
+	t.Helper()
+
+	var abbrev bytes.Buffer
+	// 1: compile unit, has children, attr: name(string)
+	abbrev.Write(uleb128(1))
+	abbrev.Write(uleb128(tagCompileUnit))
+	abbrev.WriteByte(1)
+	abbrev.Write(uleb128(atName))
+	abbrev.Write(uleb128(formString))
+	abbrev.Write(uleb128(0))
+	abbrev.Write(uleb128(0))
+	// 2: structure type, has children, attr: name(string)
+	abbrev.Write(uleb128(2))
+	abbrev.Write(uleb128(tagStructureType))
+	abbrev.WriteByte(1)
+	abbrev.Write(uleb128(atName))
+	abbrev.Write(uleb128(formString))
+	abbrev.Write(uleb128(0))
+	abbrev.Write(uleb128(0))
+	// 3: member, no children, attrs: name(string), data_member_location(data1)
+	abbrev.Write(uleb128(3))
+	abbrev.Write(uleb128(tagMember))
+	abbrev.WriteByte(0)
+	abbrev.Write(uleb128(atName))
+	abbrev.Write(uleb128(formString))
+	abbrev.Write(uleb128(atDataMemberLoc))
+	abbrev.Write(uleb128(formData1))
+	abbrev.Write(uleb128(0))
+	abbrev.Write(uleb128(0))
+	abbrev.Write(uleb128(0)) // end of abbrev table
+
+	var body bytes.Buffer
+	body.Write(uleb128(1)) // compile unit DIE
+	body.WriteString("test.go\x00")
+	body.Write(uleb128(2)) // structure type DIE
+	body.WriteString("runtime.g\x00")
+	body.Write(uleb128(3)) // member DIE
+	body.WriteString("goid\x00")
+	body.WriteByte(152)
+	body.WriteByte(0) // end of structure type children
+	body.WriteByte(0) // end of compile unit children
+
+	var info bytes.Buffer
+	unitLen := uint32(2 + 4 + 1 + body.Len())
+	_ = binary.Write(&info, binary.LittleEndian, unitLen)
+	_ = binary.Write(&info, binary.LittleEndian, uint16(2)) // DWARF version
+	_ = binary.Write(&info, binary.LittleEndian, uint32(0)) // abbrev offset
+	info.WriteByte(8)                                       // address size
+	info.Write(body.Bytes())
+
+	d, err := dwarf.New(abbrev.Bytes(), nil, nil, info.Bytes(), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("dwarf.New: %v", err)
+	}
+	return d
+}
+
+func TestStructFieldOffset(t *testing.T) {
+	d := buildTestDWARF(t)
+
+	offset, err := StructFieldOffset(d, "runtime.g", "goid")
+	if err != nil {
+		t.Fatalf("StructFieldOffset: %v", err)
+	}
+	if offset != 152 {
+		t.Fatalf("offset = %d, want 152", offset)
+	}
+}
+
+func TestStructFieldOffsetMissingField(t *testing.T) {
+	d := buildTestDWARF(t)
+
+	if _, err := StructFieldOffset(d, "runtime.g", "does_not_exist"); err == nil {
+		t.Fatal("expected error for missing field")
+	}
+}
+
+func TestStructFieldOffsetMissingStruct(t *testing.T) {
+	d := buildTestDWARF(t)
+
+	if _, err := StructFieldOffset(d, "no.such.Struct", "x"); err == nil {
+		t.Fatal("expected error for missing struct")
+	}
+}
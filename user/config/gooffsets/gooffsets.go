@@ -0,0 +1,84 @@
+// Copyright 2022 CFC4N <cfc4n.cs@gmail.com>. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gooffsets maintains a database of the runtime/crypto-tls/net struct
+// field offsets the Go TLS probe needs, keyed by the Go toolchain
+// version, GOOS and GOARCH that produced the target binary. The table is
+// consulted only as a fallback when the target binary carries no DWARF debug
+// info (e.g. built with -ldflags="-s -w"); it is regenerated by
+// cmd/genoffsets rather than hand-edited.
+package gooffsets
+
+import "fmt"
+
+// TLSConnOffsets holds the byte offsets of the crypto/tls.Conn fields the
+// probe needs to read the socket, TLS version and cipher suite of a
+// connection.
+type TLSConnOffsets struct {
+	Conn        uint64 // offset of the embedded net.Conn
+	Vers        uint64 // offset of the negotiated TLS version
+	CipherSuite uint64 // offset of the negotiated cipher suite
+}
+
+// NetFDOffsets holds the byte offsets needed to walk from a net.Conn down to
+// its underlying file descriptor.
+type NetFDOffsets struct {
+	Fd    uint64 // offset of the *netFD field within net.conn
+	Sysfd uint64 // offset of Sysfd within internal/poll.FD
+}
+
+// Offsets bundles every offset the Go TLS probe needs for one (Go version,
+// GOOS, GOARCH) combination.
+type Offsets struct {
+	GoidOffset uint64 // offset of the goid field within runtime.g
+	TLSConn    TLSConnOffsets
+	NetFD      NetFDOffsets
+}
+
+// key identifies a single row of the offset database.
+type key struct {
+	GoVersion string // "go1.20", "go1.21", ...
+	GOOS      string
+	GOARCH    string
+}
+
+// table is generated by cmd/genoffsets from a corpus of reference Go
+// binaries built with DWARF info; see that tool for the regeneration
+// procedure when a new Go release ships.
+//
+// Only rows actually produced by running cmd/genoffsets against a real
+// reference binary belong here: a hand-typed guess that turns out wrong
+// is worse than Lookup failing loudly, since the caller falls back to
+// this table only when the target binary has no DWARF info of its own
+// to check the guess against. When adding support for a Go release this
+// environment's toolchain can't build, regenerate the row on a machine
+// that has it rather than extrapolating from a neighboring version.
+var table = map[key]Offsets{
+	{"go1.21", "linux", "amd64"}: {GoidOffset: 152, TLSConn: TLSConnOffsets{Conn: 0, Vers: 72, CipherSuite: 98}, NetFD: NetFDOffsets{Fd: 0, Sysfd: 16}},
+	{"go1.21", "linux", "arm64"}: {GoidOffset: 152, TLSConn: TLSConnOffsets{Conn: 0, Vers: 72, CipherSuite: 98}, NetFD: NetFDOffsets{Fd: 0, Sysfd: 16}},
+}
+
+// Lookup returns the offset row for the given Go toolchain version ("go1.20"
+// style; the key is matched exactly, so a patch level such as "go1.20.3"
+// finds no row), GOOS and GOARCH. It returns an error
+// naming the unresolved combination when the table has no matching row,
+// typically meaning cmd/genoffsets needs to be re-run against a newer Go
+// release.
+func Lookup(goVersion, goos, goarch string) (Offsets, error) {
+	o, ok := table[key{goVersion, goos, goarch}]
+	if !ok {
+		return Offsets{}, fmt.Errorf("gooffsets: no offsets for go version %s, GOOS=%s, GOARCH=%s", goVersion, goos, goarch)
+	}
+	return o, nil
+}
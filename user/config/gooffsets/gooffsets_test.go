@@ -0,0 +1,39 @@
+// Copyright 2022 CFC4N <cfc4n.cs@gmail.com>. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gooffsets
+
+import "testing"
+
+func TestLookupKnownVersion(t *testing.T) {
+	o, err := Lookup("go1.21", "linux", "amd64")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if o.GoidOffset != 152 || o.TLSConn.CipherSuite != 98 || o.NetFD.Sysfd != 16 {
+		t.Fatalf("unexpected offsets: %+v", o)
+	}
+}
+
+func TestLookupUnknownVersion(t *testing.T) {
+	if _, err := Lookup("go1.99", "linux", "amd64"); err == nil {
+		t.Fatal("expected error for unknown Go version")
+	}
+}
+
+func TestLookupUnknownArch(t *testing.T) {
+	if _, err := Lookup("go1.21", "linux", "riscv64"); err == nil {
+		t.Fatal("expected error for unknown GOARCH")
+	}
+}